@@ -0,0 +1,87 @@
+package config
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Store holds the current Config behind an atomic pointer, so readers
+// never observe a partially-updated config and never need to take a lock.
+type Store struct {
+	path string
+	cur  atomic.Value // *Config
+
+	mu        sync.Mutex
+	listeners []func(*Config)
+}
+
+// NewStore loads path and returns a Store wrapping it.
+func NewStore(path string) (*Store, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path}
+	s.cur.Store(cfg)
+	return s, nil
+}
+
+// Current returns the most recently loaded Config snapshot. Callers must
+// not mutate it; reload a new one instead.
+func (s *Store) Current() *Config {
+	return s.cur.Load().(*Config)
+}
+
+// OnReload registers fn to be called with the new Config every time Reload
+// successfully swaps one in. Listeners are called synchronously from
+// Reload, in the order they were registered; use this to rebuild anything
+// derived from the config (e.g. the health checker's targets) instead of
+// reading Current() once at startup and never again.
+func (s *Store) OnReload(fn func(*Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload re-reads the config file and, if it parses and validates, swaps
+// it in atomically and notifies any OnReload listeners. A bad file on
+// disk leaves the previous config in place rather than taking the
+// process down.
+func (s *Store) Reload() error {
+	cfg, err := Load(s.path)
+	if err != nil {
+		return err
+	}
+	s.cur.Store(cfg)
+
+	s.mu.Lock()
+	listeners := make([]func(*Config), len(s.listeners))
+	copy(listeners, s.listeners)
+	s.mu.Unlock()
+	for _, fn := range listeners {
+		fn(cfg)
+	}
+	return nil
+}
+
+// WatchSIGHUP reloads the config every time the process receives SIGHUP,
+// logging the outcome. It returns immediately; the watch runs in its own
+// goroutine for the life of the process.
+func (s *Store) WatchSIGHUP() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go func() {
+		for range sighup {
+			if err := s.Reload(); err != nil {
+				log.Printf("config: reload of %s failed, keeping previous config: %v", s.path, err)
+				continue
+			}
+			log.Printf("config: reloaded %s", s.path)
+		}
+	}()
+}
@@ -0,0 +1,17 @@
+package dashboard
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// PageHandler serves the dashboard page, which opens a WebSocket to
+// /ws/status and renders the live grid of probe targets in the browser.
+func PageHandler(tmpl *template.Template) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("Content-type", "text/html")
+		if err := tmpl.ExecuteTemplate(response, "dashboard.tmpl", nil); err != nil {
+			http.Error(response, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
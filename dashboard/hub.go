@@ -0,0 +1,100 @@
+// Package dashboard fans health-check results out to connected browsers
+// over a WebSocket, and serves the live status page that renders them.
+package dashboard
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/JohnAntonusMaximus/golang-webserver/healthcheck"
+)
+
+// historyLimit is the number of past results kept per target for the
+// dashboard's history sparkline.
+const historyLimit = 20
+
+// subscriberBuffer bounds how many unread messages a subscriber can
+// accumulate before the hub starts dropping messages for it, so a slow
+// browser can never stall the health checker that's producing them.
+const subscriberBuffer = 16
+
+// Update is one message pushed to subscribers: the latest result for a
+// target plus its recent history, oldest first.
+type Update struct {
+	Target  string               `json:"target"`
+	Latest  healthcheck.Result   `json:"latest"`
+	History []healthcheck.Result `json:"history"`
+}
+
+// Hub fans out health-check updates to any number of WebSocket
+// subscribers and keeps a bounded history per target.
+type Hub struct {
+	mu          sync.Mutex
+	history     map[string][]healthcheck.Result
+	subscribers map[chan []byte]struct{}
+}
+
+// NewHub returns an empty Hub ready to accept Record calls and
+// subscribers.
+func NewHub() *Hub {
+	return &Hub{
+		history:     make(map[string][]healthcheck.Result),
+		subscribers: make(map[chan []byte]struct{}),
+	}
+}
+
+// Record appends r to its target's history and broadcasts an Update to
+// every current subscriber. It's meant to be used as a healthcheck.Target
+// OnResult callback.
+func (h *Hub) Record(r healthcheck.Result) {
+	h.mu.Lock()
+	hist := append(h.history[r.Target], r)
+	if len(hist) > historyLimit {
+		hist = hist[len(hist)-historyLimit:]
+	}
+	h.history[r.Target] = hist
+
+	msg, err := json.Marshal(Update{
+		Target:  r.Target,
+		Latest:  r,
+		History: append([]healthcheck.Result(nil), hist...),
+	})
+	subscribers := make([]chan []byte, 0, len(h.subscribers))
+	for ch := range h.subscribers {
+		subscribers = append(subscribers, ch)
+	}
+	h.mu.Unlock()
+
+	if err != nil {
+		log.Printf("dashboard: marshaling update: %v", err)
+		return
+	}
+	for _, ch := range subscribers {
+		select {
+		case ch <- msg:
+		default:
+			// Subscriber isn't draining fast enough; drop the update for
+			// it rather than blocking the checker goroutine that called
+			// Record.
+		}
+	}
+}
+
+// Subscribe registers a new subscriber and returns the channel updates
+// will be pushed to, along with a function that must be called to
+// unregister it once the caller is done reading.
+func (h *Hub) Subscribe() (ch chan []byte, unsubscribe func()) {
+	ch = make(chan []byte, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+		close(ch)
+	}
+}
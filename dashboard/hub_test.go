@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/JohnAntonusMaximus/golang-webserver/healthcheck"
+)
+
+func TestHubBroadcastsToSubscribers(t *testing.T) {
+	h := NewHub()
+
+	ch1, unsub1 := h.Subscribe()
+	defer unsub1()
+	ch2, unsub2 := h.Subscribe()
+	defer unsub2()
+
+	h.Record(healthcheck.Result{Target: "primary", State: "closed"})
+
+	for _, ch := range []chan []byte{ch1, ch2} {
+		select {
+		case msg := <-ch:
+			var update Update
+			if err := json.Unmarshal(msg, &update); err != nil {
+				t.Fatalf("unmarshaling update: %v", err)
+			}
+			if update.Target != "primary" {
+				t.Fatalf("update.Target = %q, want %q", update.Target, "primary")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subscriber never received the broadcast update")
+		}
+	}
+}
+
+func TestHubUnsubscribeClosesTheChannel(t *testing.T) {
+	h := NewHub()
+	ch, unsubscribe := h.Subscribe()
+	unsubscribe()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("expected the channel to be closed after unsubscribe")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reading from an unsubscribed channel should not block")
+	}
+}
+
+func TestHubTracksBoundedHistory(t *testing.T) {
+	h := NewHub()
+
+	for i := 0; i < historyLimit+5; i++ {
+		h.Record(healthcheck.Result{Target: "primary", State: "closed"})
+	}
+
+	// Check the hub's own bookkeeping directly rather than through a
+	// subscriber channel, since a channel with a bounded buffer would
+	// itself drop some of these 25 rapid-fire updates before a consumer
+	// ever had a chance to read them.
+	if got := len(h.history["primary"]); got != historyLimit {
+		t.Fatalf("len(history) = %d, want %d", got, historyLimit)
+	}
+}
+
+// TestHubRecordDoesNotBlockOnASlowSubscriber exercises the backpressure
+// path: a subscriber that never drains its channel must not be able to
+// stall the checker goroutine calling Record.
+func TestHubRecordDoesNotBlockOnASlowSubscriber(t *testing.T) {
+	h := NewHub()
+	_, unsubscribe := h.Subscribe() // never read from
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < subscriberBuffer*4; i++ {
+			h.Record(healthcheck.Result{Target: "primary", State: "closed"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a subscriber that wasn't draining its channel")
+	}
+}
@@ -0,0 +1,50 @@
+package dashboard
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// writeTimeout bounds how long a single write to a subscriber's
+// connection may take, so a stalled client is noticed and dropped.
+const writeTimeout = 5 * time.Second
+
+// ServeWS upgrades the request to a WebSocket and streams hub updates to
+// it until the client disconnects.
+func (h *Hub) ServeWS(response http.ResponseWriter, request *http.Request) {
+	conn, err := upgrader.Upgrade(response, request, nil)
+	if err != nil {
+		log.Printf("dashboard: websocket upgrade: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	updates, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+
+	// Discard anything the browser sends us; we only push. Reading keeps
+	// the connection's control frames (ping/close) flowing.
+	go func() {
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}()
+
+	for msg := range updates {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			return
+		}
+	}
+}
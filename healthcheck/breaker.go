@@ -0,0 +1,98 @@
+package healthcheck
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed means the target is healthy and checks pass through normally.
+	StateClosed State = iota
+	// StateOpen means the target is considered down; it is not retried
+	// until the backoff has elapsed.
+	StateOpen
+	// StateHalfOpen means the backoff has elapsed and the breaker is
+	// probing to see whether the target has recovered.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// breaker is a simple closed/open/half-open circuit breaker. It is not
+// safe for concurrent use; callers must hold their own lock (Checker does).
+type breaker struct {
+	state State
+
+	failureThreshold int // consecutive failures to trip closed -> open
+	successThreshold int // consecutive successes to trip half-open -> closed
+
+	consecutiveFailures  int
+	consecutiveSuccesses int
+}
+
+func newBreaker(failureThreshold, successThreshold int) *breaker {
+	if failureThreshold <= 0 {
+		failureThreshold = 3
+	}
+	if successThreshold <= 0 {
+		successThreshold = 2
+	}
+	return &breaker{
+		failureThreshold: failureThreshold,
+		successThreshold: successThreshold,
+	}
+}
+
+// allow reports whether a check should be attempted. An open breaker only
+// allows a probe once it has been moved to half-open by the caller.
+func (b *breaker) allow() bool {
+	return b.state != StateOpen
+}
+
+func (b *breaker) recordSuccess() {
+	b.consecutiveFailures = 0
+	switch b.state {
+	case StateHalfOpen:
+		b.consecutiveSuccesses++
+		if b.consecutiveSuccesses >= b.successThreshold {
+			b.state = StateClosed
+			b.consecutiveSuccesses = 0
+		}
+	case StateOpen:
+		// Shouldn't happen; allow() should have kept us from checking.
+		b.state = StateHalfOpen
+		b.consecutiveSuccesses = 1
+	}
+}
+
+func (b *breaker) recordFailure() {
+	b.consecutiveSuccesses = 0
+	switch b.state {
+	case StateClosed:
+		b.consecutiveFailures++
+		if b.consecutiveFailures >= b.failureThreshold {
+			b.state = StateOpen
+			b.consecutiveFailures = 0
+		}
+	case StateHalfOpen:
+		b.state = StateOpen
+		b.consecutiveFailures = 0
+	}
+}
+
+// halfOpen transitions an open breaker to half-open so the next check can
+// be attempted. Called by the checker once the backoff delay has elapsed.
+func (b *breaker) halfOpen() {
+	if b.state == StateOpen {
+		b.state = StateHalfOpen
+	}
+}
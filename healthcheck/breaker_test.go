@@ -0,0 +1,118 @@
+package healthcheck
+
+import "testing"
+
+// step is one action applied to a breaker in a table-driven test case.
+type step struct {
+	action string // "success", "failure", or "halfOpen"
+	want   State  // state expected immediately after the action
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	tests := []struct {
+		name             string
+		failureThreshold int
+		successThreshold int
+		steps            []step
+	}{
+		{
+			name:             "opens after consecutive failures reach the threshold",
+			failureThreshold: 3,
+			successThreshold: 2,
+			steps: []step{
+				{"failure", StateClosed},
+				{"failure", StateClosed},
+				{"failure", StateOpen},
+			},
+		},
+		{
+			name:             "a success before the threshold resets the failure count",
+			failureThreshold: 3,
+			successThreshold: 2,
+			steps: []step{
+				{"failure", StateClosed},
+				{"failure", StateClosed},
+				{"success", StateClosed},
+				{"failure", StateClosed},
+				{"failure", StateClosed},
+				{"failure", StateOpen},
+			},
+		},
+		{
+			name:             "half-open closes after consecutive successes reach the threshold",
+			failureThreshold: 3,
+			successThreshold: 2,
+			steps: []step{
+				{"failure", StateClosed},
+				{"failure", StateClosed},
+				{"failure", StateOpen},
+				{"halfOpen", StateHalfOpen},
+				{"success", StateHalfOpen},
+				{"success", StateClosed},
+			},
+		},
+		{
+			name:             "half-open reopens on a single failure",
+			failureThreshold: 3,
+			successThreshold: 2,
+			steps: []step{
+				{"failure", StateClosed},
+				{"failure", StateClosed},
+				{"failure", StateOpen},
+				{"halfOpen", StateHalfOpen},
+				{"success", StateHalfOpen},
+				{"failure", StateOpen},
+			},
+		},
+		{
+			name:             "halfOpen is a no-op on a closed breaker",
+			failureThreshold: 3,
+			successThreshold: 2,
+			steps: []step{
+				{"halfOpen", StateClosed},
+				{"success", StateClosed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(tt.failureThreshold, tt.successThreshold)
+			for i, s := range tt.steps {
+				switch s.action {
+				case "success":
+					b.recordSuccess()
+				case "failure":
+					b.recordFailure()
+				case "halfOpen":
+					b.halfOpen()
+				default:
+					t.Fatalf("step %d: unknown action %q", i, s.action)
+				}
+				if b.state != s.want {
+					t.Fatalf("step %d (%s): state = %s, want %s", i, s.action, b.state, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerAllow(t *testing.T) {
+	b := newBreaker(1, 1)
+	if !b.allow() {
+		t.Fatal("a fresh (closed) breaker should allow checks")
+	}
+
+	b.recordFailure()
+	if b.state != StateOpen {
+		t.Fatalf("state = %s, want %s", b.state, StateOpen)
+	}
+	if b.allow() {
+		t.Fatal("an open breaker should not allow checks")
+	}
+
+	b.halfOpen()
+	if !b.allow() {
+		t.Fatal("a half-open breaker should allow checks")
+	}
+}
@@ -0,0 +1,206 @@
+package healthcheck
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Result is a point-in-time snapshot of a target's health.
+type Result struct {
+	Target    string    `json:"target"`
+	State     string    `json:"state"`
+	LastCheck time.Time `json:"last_check"`
+	Latency   string    `json:"latency"`
+	Err       string    `json:"error,omitempty"`
+}
+
+// Target ties a named Probe to its check cadence and circuit breaker
+// thresholds.
+type Target struct {
+	Name    string
+	Probe   Probe
+	Timeout time.Duration // per-check timeout, defaults to 5s
+
+	Interval         time.Duration // steady-state check interval, defaults to 10s
+	MaxBackoff       time.Duration // cap on backoff while the breaker is open, defaults to 10x Interval
+	FailureThreshold int           // consecutive failures to open the breaker, defaults to 3
+	SuccessThreshold int           // consecutive successes to close the breaker, defaults to 2
+
+	// OnResult, if set, is called after every check with the target's
+	// updated Result. It runs outside the Checker's lock, so it's safe
+	// for it to call back into the Checker (e.g. Healthy).
+	OnResult func(Result)
+}
+
+type target struct {
+	Target
+	breaker *breaker
+	result  Result
+	backoff time.Duration
+}
+
+// Checker runs probes for a set of targets on independent schedules and
+// tracks each target's circuit breaker state.
+type Checker struct {
+	mu      sync.RWMutex
+	targets map[string]*target
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewChecker builds a Checker for the given targets. It does not start
+// checking until Start is called.
+func NewChecker(targets ...Target) *Checker {
+	c := &Checker{
+		targets: make(map[string]*target, len(targets)),
+		stop:    make(chan struct{}),
+	}
+	for _, t := range targets {
+		if t.Timeout <= 0 {
+			t.Timeout = 5 * time.Second
+		}
+		if t.Interval <= 0 {
+			t.Interval = 10 * time.Second
+		}
+		if t.MaxBackoff <= 0 {
+			t.MaxBackoff = 10 * t.Interval
+		}
+		c.targets[t.Name] = &target{
+			Target:  t,
+			breaker: newBreaker(t.FailureThreshold, t.SuccessThreshold),
+			backoff: t.Interval,
+			result:  Result{Target: t.Name, State: StateClosed.String()},
+		}
+	}
+	return c
+}
+
+// Start begins checking every target on its own goroutine. It returns
+// immediately; call Stop to shut the goroutines down.
+func (c *Checker) Start() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, t := range c.targets {
+		c.wg.Add(1)
+		go c.run(t)
+	}
+}
+
+// Stop halts all checking goroutines and waits for them to exit.
+func (c *Checker) Stop() {
+	close(c.stop)
+	c.wg.Wait()
+}
+
+func (c *Checker) run(t *target) {
+	defer c.wg.Done()
+
+	timer := time.NewTimer(0) // check once immediately on startup
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-timer.C:
+			c.probeOnce(t)
+			timer.Reset(c.nextDelay(t))
+		}
+	}
+}
+
+func (c *Checker) nextDelay(t *target) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if t.breaker.state == StateClosed {
+		t.backoff = t.Interval
+		return t.Interval
+	}
+	// Open or half-open: back off exponentially up to MaxBackoff.
+	delay := t.backoff
+	t.backoff *= 2
+	if t.backoff > t.MaxBackoff {
+		t.backoff = t.MaxBackoff
+	}
+	return delay
+}
+
+func (c *Checker) probeOnce(t *target) {
+	c.mu.Lock()
+	if t.breaker.state == StateOpen {
+		t.breaker.halfOpen()
+	}
+	allowed := t.breaker.allow()
+	c.mu.Unlock()
+	if !allowed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := t.Probe.Check(ctx)
+	latency := time.Since(start)
+
+	c.mu.Lock()
+	if err != nil {
+		t.breaker.recordFailure()
+		t.result.Err = err.Error()
+	} else {
+		t.breaker.recordSuccess()
+		t.result.Err = ""
+	}
+	t.result.State = t.breaker.state.String()
+	t.result.LastCheck = time.Now()
+	t.result.Latency = latency.String()
+	result := t.result
+	onResult := t.OnResult
+	c.mu.Unlock()
+
+	if onResult != nil {
+		onResult(result)
+	}
+}
+
+// Results returns a snapshot of every target's current health.
+func (c *Checker) Results() []Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results := make([]Result, 0, len(c.targets))
+	for _, t := range c.targets {
+		results = append(results, t.result)
+	}
+	return results
+}
+
+// Healthy reports whether the named target's breaker is closed (i.e. the
+// target is currently considered up). Unknown targets are reported
+// unhealthy.
+func (c *Checker) Healthy(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	t, ok := c.targets[name]
+	if !ok {
+		return false
+	}
+	return t.breaker.state == StateClosed
+}
+
+// Ready reports whether every target is currently closed. It is intended
+// for use by a readiness probe: a process isn't ready to serve if one of
+// its dependencies is known to be down.
+func (c *Checker) Ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, t := range c.targets {
+		if t.breaker.state != StateClosed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package healthcheck
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failUntil is a Probe that fails its first n checks and succeeds
+// afterward; n is decremented on every check.
+type failUntil struct {
+	n int32
+}
+
+func (p *failUntil) Check(ctx context.Context) error {
+	if atomic.AddInt32(&p.n, -1) >= 0 {
+		return errProbe
+	}
+	return nil
+}
+
+var errProbe = &probeError{"probe failed"}
+
+type probeError struct{ msg string }
+
+func (e *probeError) Error() string { return e.msg }
+
+// eventually polls fn every 5ms until it returns true or timeout elapses.
+func eventually(t *testing.T, timeout time.Duration, fn func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if fn() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("condition not met within %s", timeout)
+}
+
+func TestCheckerOpensAndRecoversOverTime(t *testing.T) {
+	probe := &failUntil{n: 2} // fail the first two checks, then succeed
+
+	var onResultCalls int32
+	c := NewChecker(Target{
+		Name:             "flaky",
+		Probe:            probe,
+		Interval:         10 * time.Millisecond,
+		Timeout:          50 * time.Millisecond,
+		FailureThreshold: 2,
+		SuccessThreshold: 1,
+		OnResult: func(r Result) {
+			atomic.AddInt32(&onResultCalls, 1)
+		},
+	})
+	c.Start()
+	defer c.Stop()
+
+	// Two consecutive failures should open the breaker.
+	eventually(t, time.Second, func() bool {
+		return !c.Healthy("flaky")
+	})
+
+	// Once open, the checker retries after its backoff delay; the next
+	// (succeeding) check closes the breaker again.
+	eventually(t, time.Second, func() bool {
+		return c.Healthy("flaky")
+	})
+
+	if atomic.LoadInt32(&onResultCalls) == 0 {
+		t.Fatal("OnResult was never called")
+	}
+}
+
+func TestCheckerUnknownTargetIsUnhealthy(t *testing.T) {
+	c := NewChecker(Target{Name: "known", Probe: FuncProbe(func(ctx context.Context) error { return nil })})
+	if c.Healthy("missing") {
+		t.Fatal("an unregistered target should report unhealthy")
+	}
+}
+
+func TestCheckerReadyReflectsAllTargets(t *testing.T) {
+	c := NewChecker(
+		Target{Name: "up", Probe: FuncProbe(func(ctx context.Context) error { return nil }), FailureThreshold: 1},
+		Target{Name: "down", Probe: FuncProbe(func(ctx context.Context) error { return errProbe }), FailureThreshold: 1},
+	)
+	c.Start()
+	defer c.Stop()
+
+	eventually(t, time.Second, func() bool {
+		return !c.Ready()
+	})
+	if c.Healthy("up") == false {
+		t.Fatal("the always-succeeding target should stay healthy")
+	}
+}
+
+func TestNextDelayBacksOffExponentiallyWhileOpen(t *testing.T) {
+	c := NewChecker(Target{
+		Name:       "t",
+		Probe:      FuncProbe(func(ctx context.Context) error { return nil }),
+		Interval:   10 * time.Millisecond,
+		MaxBackoff: 40 * time.Millisecond,
+	})
+	tg := c.targets["t"]
+
+	// Force the breaker open without starting the checker goroutine, so
+	// nextDelay's scheduling can be tested deterministically.
+	tg.breaker.state = StateOpen
+
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	for i, w := range want {
+		if got := c.nextDelay(tg); got != w {
+			t.Fatalf("nextDelay call %d = %s, want %s", i, got, w)
+		}
+	}
+}
+
+func TestNextDelayResetsOnceClosed(t *testing.T) {
+	c := NewChecker(Target{
+		Name:       "t",
+		Probe:      FuncProbe(func(ctx context.Context) error { return nil }),
+		Interval:   10 * time.Millisecond,
+		MaxBackoff: 40 * time.Millisecond,
+	})
+	tg := c.targets["t"]
+
+	tg.breaker.state = StateOpen
+	c.nextDelay(tg)
+	c.nextDelay(tg) // backoff has grown past Interval by now
+
+	tg.breaker.state = StateClosed
+	if got := c.nextDelay(tg); got != tg.Interval {
+		t.Fatalf("nextDelay after closing = %s, want %s", got, tg.Interval)
+	}
+}
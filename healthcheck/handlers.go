@@ -0,0 +1,41 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// statusResponse is the JSON body returned by both /healthz and /readyz.
+type statusResponse struct {
+	Status  string   `json:"status"`
+	Targets []Result `json:"targets"`
+}
+
+// LivenessHandler always returns 200 with the current per-target status,
+// since liveness just answers "is the process alive", not "are the
+// targets healthy". Use ReadinessHandler to gate on target health.
+func (c *Checker) LivenessHandler(response http.ResponseWriter, request *http.Request) {
+	writeStatus(response, http.StatusOK, "alive", c.Results())
+}
+
+// ReadinessHandler returns 200 when every target's breaker is closed, and
+// 503 otherwise, so a load balancer can stop sending traffic while a
+// dependency is down.
+func (c *Checker) ReadinessHandler(response http.ResponseWriter, request *http.Request) {
+	code := http.StatusOK
+	status := "ready"
+	if !c.Ready() {
+		code = http.StatusServiceUnavailable
+		status = "not ready"
+	}
+	writeStatus(response, code, status, c.Results())
+}
+
+func writeStatus(response http.ResponseWriter, code int, status string, results []Result) {
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(code)
+	json.NewEncoder(response).Encode(statusResponse{
+		Status:  status,
+		Targets: results,
+	})
+}
@@ -0,0 +1,71 @@
+// Package healthcheck implements pluggable health probes behind a
+// per-target circuit breaker, so that a single flaky response doesn't
+// flip a site between primary and failover.
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Probe checks whether a single target is healthy. Implementations should
+// respect ctx and return promptly when it is cancelled.
+type Probe interface {
+	Check(ctx context.Context) error
+}
+
+// HTTPProbe issues an HTTP request and considers any 2xx response healthy.
+type HTTPProbe struct {
+	URL    string
+	Method string // defaults to http.MethodGet
+	Client *http.Client
+}
+
+func (p *HTTPProbe) Check(ctx context.Context) error {
+	method := p.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.URL, nil)
+	if err != nil {
+		return fmt.Errorf("healthcheck: building request: %w", err)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("healthcheck: %s %s: %w", method, p.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("healthcheck: %s %s: status %d", method, p.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// TCPDialProbe considers a target healthy if a TCP connection can be
+// established within the probe's deadline.
+type TCPDialProbe struct {
+	Addr string
+}
+
+func (p *TCPDialProbe) Check(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", p.Addr)
+	if err != nil {
+		return fmt.Errorf("healthcheck: dial %s: %w", p.Addr, err)
+	}
+	return conn.Close()
+}
+
+// FuncProbe adapts a plain function to the Probe interface.
+type FuncProbe func(ctx context.Context) error
+
+func (f FuncProbe) Check(ctx context.Context) error { return f(ctx) }
@@ -0,0 +1,43 @@
+package observability
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// requestDiagnostics is a structured log line describing one request's
+// method, URL and form data — the same fields GenericHandler used to dump
+// straight into the response body.
+type requestDiagnostics struct {
+	Event   string   `json:"event"`
+	Method  string   `json:"method"`
+	URI     string   `json:"request_uri"`
+	Path    string   `json:"path"`
+	Form    string   `json:"form"`
+	Cookies []string `json:"cookies"`
+}
+
+// LogRequestDiagnostics emits a structured JSON log line for request,
+// tagged with event, using the same logger and JSON-line convention as
+// AccessLog so the two don't produce two different log formats.
+func LogRequestDiagnostics(event string, request *http.Request) {
+	cookies := make([]string, 0, len(request.Cookies()))
+	for _, c := range request.Cookies() {
+		cookies = append(cookies, c.String())
+	}
+
+	line, err := json.Marshal(requestDiagnostics{
+		Event:   event,
+		Method:  request.Method,
+		URI:     request.RequestURI,
+		Path:    request.URL.Path,
+		Form:    request.Form.Encode(),
+		Cookies: cookies,
+	})
+	if err != nil {
+		log.Printf("observability: marshaling request diagnostics: %v", err)
+		return
+	}
+	log.Print(string(line))
+}
@@ -0,0 +1,65 @@
+// Package observability wraps the mux with access logging and exposes
+// Prometheus metrics and pprof profiles, so request latency and
+// health-check behavior can be diagnosed in production.
+package observability
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests, labeled by method, path and status.",
+	}, []string{"method", "path", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, labeled by method and path.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path"})
+
+	healthcheckFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "healthcheck_failures_total",
+		Help: "Total number of failed health checks, labeled by target.",
+	}, []string{"target"})
+
+	failoverActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "failover_active",
+		Help: "1 if the labeled target's circuit breaker is not closed, 0 otherwise.",
+	}, []string{"target"})
+)
+
+// RecordHealthCheck updates the healthcheck_failures_total and
+// failover_active metrics for a single probe result.
+func RecordHealthCheck(target, state string, failed bool) {
+	if failed {
+		healthcheckFailuresTotal.WithLabelValues(target).Inc()
+	}
+	active := 0.0
+	if state != "closed" {
+		active = 1.0
+	}
+	failoverActive.WithLabelValues(target).Set(active)
+}
+
+// MetricsHandler serves the Prometheus exposition format for scraping.
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterPprof mounts the standard net/http/pprof handlers onto mux under
+// /debug/pprof/. Callers should only do this behind an explicit flag;
+// pprof exposes stack traces and profiling data that shouldn't be public.
+func RegisterPprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
@@ -4,35 +4,127 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
 	"fmt"
 	"html/template"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/JohnAntonusMaximus/golang-webserver/config"
+	"github.com/JohnAntonusMaximus/golang-webserver/dashboard"
+	"github.com/JohnAntonusMaximus/golang-webserver/healthcheck"
+	"github.com/JohnAntonusMaximus/golang-webserver/observability"
 )
 
 var (
-	url      = "https://s3.amazonaws.com/react-web/mona-lisa.jpg"
-	styles   = "https://s3.amazonaws.com/react-web/styles.css"
-	url2     = ""
-	styles2  = ""
-	failover = false
-	tmpl     *template.Template
-)
+	configPath = flag.String("config", "webserver-config.json", "path to the JSON config file (reloaded on SIGHUP)")
+	pprofFlag  = flag.Bool("pprof", false, "mount /debug/pprof/* profiling endpoints")
+	debugFlag  = flag.Bool("debug", false, "include request diagnostics in the /generic response body")
 
-// Original Image:
-// https://images.pexels.com/photos/104827/cat-pet-animal-domestic-104827.jpeg?auto=compress&cs=tinysrgb&h=350
+	tmpl  *template.Template
+	store *config.Store
+
+	// checkerVal holds the live *healthcheck.Checker. It's rebuilt from
+	// scratch on every config reload (see buildChecker/store.OnReload
+	// below) since a Target's probe closure captures its tier's URLs by
+	// value, so swapping the config.Store's pointer alone wouldn't change
+	// what's actually being probed.
+	checkerVal atomic.Value
+)
 
 func init() {
-	if len(os.Args) > 1 {
-		url2 = os.Args[1]
-		styles2 = os.Args[2]
-	}
+	flag.Parse()
 	tmpl = template.Must(template.ParseGlob("html/*"))
 }
 
+func currentChecker() *healthcheck.Checker {
+	return checkerVal.Load().(*healthcheck.Checker)
+}
+
+// buildChecker creates and starts a Checker with one target per tier in
+// cfg, wired to report into hub and the observability metrics.
+func buildChecker(cfg *config.Config, hub *dashboard.Hub) *healthcheck.Checker {
+	targets := make([]healthcheck.Target, 0, len(cfg.Tiers))
+	for _, tier := range cfg.Tiers {
+		tier := tier // capture for the closure below
+		targets = append(targets, healthcheck.Target{
+			Name: tier.Name,
+			Probe: healthcheck.FuncProbe(func(ctx context.Context) error {
+				for _, site := range []string{tier.ImageURL, tier.StylesURL} {
+					if err := (&healthcheck.HTTPProbe{URL: site}).Check(ctx); err != nil {
+						return err
+					}
+				}
+				return nil
+			}),
+			Interval:         cfg.CheckInterval(),
+			Timeout:          5 * time.Second,
+			FailureThreshold: 3,
+			SuccessThreshold: 2,
+			OnResult: func(r healthcheck.Result) {
+				observability.RecordHealthCheck(r.Target, r.State, r.Err != "")
+				hub.Record(r)
+			},
+		})
+	}
+	c := healthcheck.NewChecker(targets...)
+	c.Start()
+	return c
+}
+
+// assetURL returns the URL the home page should use for the given asset
+// filename: a /static/ path when content_dir is set and the file exists,
+// is non-empty, and (when a checksum is configured for it) matches its
+// expected sha256 digest; it falls back to remote otherwise.
+func assetURL(cfg *config.Config, name, remote string) string {
+	if cfg.ContentDir == "" {
+		return remote
+	}
+	path := filepath.Join(cfg.ContentDir, name)
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() == 0 {
+		return remote
+	}
+
+	if want, ok := cfg.ContentChecksums[name]; ok {
+		got, err := fileSHA256(path)
+		if err != nil || got != want {
+			return remote
+		}
+	}
+
+	return "/static/" + name
+}
+
+// fileSHA256 returns the lowercase hex-encoded sha256 digest of the file
+// at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func SetMyCookie(response http.ResponseWriter) {
 	// Add a simplistic cookie to the response.
 	cookie := http.Cookie{Name: "testcookiename", Value: "testcookievalue"}
@@ -52,103 +144,126 @@ func GenericHandler(response http.ResponseWriter, request *http.Request) {
 		http.Error(response, fmt.Sprintf("error parsing url %v", err), 500)
 	}
 
-	// Send the text diagnostics to the client.
-	fmt.Fprint(response, "WebServerStatus says ... \n")
-	fmt.Fprintf(response, " request.Method     '%v'\n", request.Method)
-	fmt.Fprintf(response, " request.RequestURI '%v'\n", request.RequestURI)
-	fmt.Fprintf(response, " request.URL.Path   '%v'\n", request.URL.Path)
-	fmt.Fprintf(response, " request.Form       '%v'\n", request.Form)
-	fmt.Fprintf(response, " request.Cookies()  '%v'\n", request.Cookies())
+	observability.LogRequestDiagnostics("generic", request)
+
+	if *debugFlag {
+		fmt.Fprint(response, "WebServerStatus says ... \n")
+		fmt.Fprintf(response, " request.Method     '%v'\n", request.Method)
+		fmt.Fprintf(response, " request.RequestURI '%v'\n", request.RequestURI)
+		fmt.Fprintf(response, " request.URL.Path   '%v'\n", request.URL.Path)
+		fmt.Fprintf(response, " request.Form       '%v'\n", request.Form)
+		fmt.Fprintf(response, " request.Cookies()  '%v'\n", request.Cookies())
+		return
+	}
+	fmt.Fprint(response, "WebServerStatus says ... ok\n")
 }
 
 // Respond to the URL /home with an html home page
 func HomeHandler(response http.ResponseWriter, request *http.Request) {
 	response.Header().Set("Content-type", "text/html")
-	if failover == false {
-		source := map[string]interface{}{
-			"img": url,
-			"css": styles,
-		}
-		err := tmpl.ExecuteTemplate(response, "home.tmpl", source)
-		if err != nil {
-			panic(err)
-		}
-	} else {
-		source := map[string]interface{}{
-			"img": url2,
-			"css": styles2,
-		}
-		err := tmpl.ExecuteTemplate(response, "home.tmpl", source)
-		if err != nil {
-			panic(err)
+
+	cfg := store.Current()
+	tier := activeTier(cfg)
+
+	source := map[string]interface{}{
+		"img": assetURL(cfg, "mona-lisa.jpg", tier.ImageURL),
+		"css": assetURL(cfg, "styles.css", tier.StylesURL),
+	}
+
+	err := tmpl.ExecuteTemplate(response, "home.tmpl", source)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// activeTier walks the configured failover ladder and returns the first
+// tier the checker still considers healthy, falling back to the last
+// tier (typically the most "final" fallback) if every tier is down.
+func activeTier(cfg *config.Config) config.AssetTier {
+	checker := currentChecker()
+	for _, tier := range cfg.Tiers {
+		if checker.Healthy(tier.Name) {
+			return tier
 		}
 	}
+	return cfg.Tiers[len(cfg.Tiers)-1]
 }
 
 func main() {
-	sites := []string{
-		url,
-		styles,
-	}
-
-	status := make(chan string)
-	ticker := time.NewTicker(10 * time.Second)
-	failoverChan := make(chan bool)
-	var buffer int
-
-	go func(s []string) {
-		for {
-			select {
-			case <-ticker.C:
-				buffer = len(s)
-				checkLinks(s, status, failoverChan)
-
-			case <-failoverChan:
-				failover = true
-			}
-		}
-	}(sites)
+	var err error
+	store, err = config.NewStore(*configPath)
+	if err != nil {
+		log.Fatal("config error: ", err)
+	}
+	cfg := store.Current()
+
+	hub := dashboard.NewHub()
+
+	checkerVal.Store(buildChecker(cfg, hub))
+	store.OnReload(func(newCfg *config.Config) {
+		old := currentChecker()
+		checkerVal.Store(buildChecker(newCfg, hub))
+		old.Stop()
+	})
+	store.WatchSIGHUP()
+	defer currentChecker().Stop()
 
-	port := 8097
-	portstring := strconv.Itoa(port)
+	portstring := strconv.Itoa(cfg.Port)
 
 	mux := http.NewServeMux()
 	mux.Handle("/", http.HandlerFunc(HomeHandler))
 	mux.Handle("/generic", http.HandlerFunc(GenericHandler))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { currentChecker().LivenessHandler(w, r) })
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) { currentChecker().ReadinessHandler(w, r) })
+	mux.Handle("/metrics", observability.MetricsHandler())
+	mux.HandleFunc("/ws/status", hub.ServeWS)
+	mux.Handle("/dashboard", dashboard.PageHandler(tmpl))
+	if cfg.ContentDir != "" {
+		mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(cfg.ContentDir))))
+	}
+	if *pprofFlag {
+		observability.RegisterPprof(mux)
+	}
 
-	go func() {
-		for {
-			fmt.Println(<-status)
-		}
-	}()
+	handler := observability.AccessLog(mux)
 
-	go func() {
-		log.Print("Listening on port " + portstring + " ... ")
-		err := http.ListenAndServe(":"+portstring, mux)
-		if err != nil {
-			log.Fatal("ListenAndServe error: ", err)
+	if cfg.HTTPSAddr != "" {
+		certManager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertHosts...),
+			Cache:      autocert.DirCache("certs"),
 		}
-	}()
+		server := &http.Server{
+			Addr:      cfg.HTTPSAddr,
+			Handler:   handler,
+			TLSConfig: certManager.TLSConfig(),
+		}
+		go func() {
+			log.Print("Listening on " + cfg.HTTPSAddr + " (https) ... ")
+			log.Fatal(server.ListenAndServeTLS("", ""))
+		}()
+
+		// Let autocert answer ACME HTTP-01 challenges on the plain HTTP
+		// port, and redirect everything else to HTTPS.
+		handler = certManager.HTTPHandler(http.HandlerFunc(redirectToHTTPS(cfg.RedirectCode)))
+	}
 
-	// Blocks on the main thread
-	select {}
+	log.Print("Listening on port " + portstring + " ... ")
+	err = http.ListenAndServe(cfg.Host+":"+portstring, handler)
+	if err != nil {
+		log.Fatal("ListenAndServe error: ", err)
+	}
 }
 
-func checkLinks(sites []string, status chan string, failoverChan chan bool) {
-	for _, site := range sites {
-		go func(s string) {
-			resp, err := http.Get(s)
-			if err != nil {
-				fmt.Println("Error Fetching Object! Failing over... ", err)
-				failoverChan <- true
-			}
-			if resp.StatusCode != 200 {
-				fmt.Println("Error Fetching Object! Failing over... ", " - DOWN")
-				failoverChan <- true
-			} else {
-				status <- s + " - OK"
-				failover = false
-			}
-		}(site)
+// redirectToHTTPS sends browsers hitting the plain HTTP port to the HTTPS
+// listener on the same host, using the configured redirect status code.
+func redirectToHTTPS(code int) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		host := request.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		target := "https://" + host + request.URL.RequestURI()
+		http.Redirect(response, request, target, code)
 	}
 }